@@ -0,0 +1,200 @@
+/*
+Copyright 2015 Palm Stone Games, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymer
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// mutationKind identifies the shape of a recorded mutation inside a domBatch, used to decide which
+// adjacent writes against the same parent/node can be coalesced away
+type mutationKind int
+
+const (
+	mutationAppend mutationKind = iota
+	mutationInsertBefore
+	mutationRemove
+	mutationSetAttribute
+)
+
+// mutation is a single DOM write deferred while a domBatch is open
+// parent/node are kept alongside apply purely so coalesce can recognize adjacent writes
+// against the same parent; apply is what actually performs the write once the batch flushes.
+type mutation struct {
+	kind   mutationKind
+	parent *js.Object
+	node   *js.Object
+	apply  func()
+}
+
+// domBatch is an open mutation batch started by BatchDOM
+// Batches are reentrant: a BatchDOM call nested inside another joins the outer batch rather than
+// opening a second one, and only the outermost call applies its writes and flushes.
+type domBatch struct {
+	ops []mutation
+}
+
+// currentBatch is the batch writes on WrappedElement record into, or nil when no batch is open, in
+// which case writes apply immediately as before
+var currentBatch *domBatch
+
+// BatchDOM opens a mutation batch: every AppendChild/InsertBefore/RemoveChild/attribute write
+// performed on a WrappedElement inside f is deferred rather than applied immediately, adjacent
+// inserts/removes on the same parent/node are coalesced away, and the surviving writes are applied
+// and flushed exactly once via Polymer.dom.flush when f returns or panics.
+// Nested BatchDOM calls join the outermost batch instead of flushing early.
+func BatchDOM(f func()) {
+	if currentBatch != nil {
+		f()
+		return
+	}
+
+	currentBatch = &domBatch{}
+	defer func() {
+		batch := currentBatch
+		currentBatch = nil
+		for _, op := range batch.coalesce() {
+			op.apply()
+		}
+		FlushDOM()
+	}()
+
+	f()
+}
+
+// WithoutBatch is the escape hatch for code that needs to interrogate the DOM synchronously: it
+// suspends the current batch (if any) for the duration of f, so writes made by f apply immediately
+// and are flushed right away rather than being deferred to the enclosing BatchDOM.
+func WithoutBatch(f func()) {
+	outer := currentBatch
+	currentBatch = nil
+	defer func() { currentBatch = outer }()
+
+	f()
+	FlushDOM()
+}
+
+// coalesce drops an insert immediately undone by a remove of the same node on the same parent,
+// since applying neither has the same net effect on the DOM as applying both
+func (b *domBatch) coalesce() []mutation {
+	coalesced := make([]mutation, 0, len(b.ops))
+	for _, op := range b.ops {
+		if n := len(coalesced); n > 0 {
+			prev := coalesced[n-1]
+			if prev.parent == op.parent && prev.node == op.node &&
+				(prev.kind == mutationAppend || prev.kind == mutationInsertBefore) && op.kind == mutationRemove {
+				coalesced = coalesced[:n-1]
+				continue
+			}
+		}
+		coalesced = append(coalesced, op)
+	}
+	return coalesced
+}
+
+// recordOrApply defers op to the currently open batch, or applies it immediately if no batch is
+// open
+func recordOrApply(op mutation) {
+	if currentBatch != nil {
+		currentBatch.ops = append(currentBatch.ops, op)
+		return
+	}
+	op.apply()
+}
+
+var microtaskQueue []func()
+var microtaskObserver *js.Object
+var microtaskToggle *js.Object
+var microtaskToggleState bool
+
+// ensureMicrotaskObserver lazily wires up the MutationObserver-on-a-text-node trick the ShadowDOM
+// polyfill's microtask.js uses to schedule a true microtask in browsers without native support
+func ensureMicrotaskObserver() {
+	if microtaskObserver != nil {
+		return
+	}
+
+	microtaskToggle = js.Global.Get("document").Call("createTextNode", "")
+	microtaskObserver = js.Global.Get("MutationObserver").New(func() {
+		queue := microtaskQueue
+		microtaskQueue = nil
+		for _, f := range queue {
+			f()
+		}
+	})
+	microtaskObserver.Call("observe", microtaskToggle, map[string]interface{}{"characterData": true})
+}
+
+// ScheduleMicrotask defers f to run after the current task, before the next paint/event, using the
+// same MutationObserver-based microtask primitive the ShadowDOM polyfill relies on
+func ScheduleMicrotask(f func()) {
+	ensureMicrotaskObserver()
+	microtaskQueue = append(microtaskQueue, f)
+	microtaskToggleState = !microtaskToggleState
+	if microtaskToggleState {
+		microtaskToggle.Set("textContent", "1")
+	} else {
+		microtaskToggle.Set("textContent", "")
+	}
+}
+
+// EndOfMicrotask defers f until after every microtask already queued via ScheduleMicrotask (at the
+// time of the call) has drained, so callers can read layout/style once Polymer has finished
+// distributing in response to those microtasks
+func EndOfMicrotask(f func()) {
+	ScheduleMicrotask(func() {
+		ScheduleMicrotask(f)
+	})
+}
+
+// AppendChild appends node as a child of el, deferring the write if a BatchDOM is open
+func (el *WrappedElement) AppendChild(node dom.Node) {
+	parent := el.Underlying()
+	target := unwrap(node.Underlying())
+	recordOrApply(mutation{kind: mutationAppend, parent: parent, node: target, apply: func() {
+		parent.Call("appendChild", target)
+	}})
+}
+
+// InsertBefore inserts which before the before node under el, deferring the write if a BatchDOM is
+// open
+func (el *WrappedElement) InsertBefore(which dom.Node, before dom.Node) {
+	parent := el.Underlying()
+	target := unwrap(which.Underlying())
+	beforeTarget := unwrap(before.Underlying())
+	recordOrApply(mutation{kind: mutationInsertBefore, parent: parent, node: target, apply: func() {
+		parent.Call("insertBefore", target, beforeTarget)
+	}})
+}
+
+// RemoveChild removes node from el, deferring the write if a BatchDOM is open
+func (el *WrappedElement) RemoveChild(node dom.Node) {
+	parent := el.Underlying()
+	target := unwrap(node.Underlying())
+	recordOrApply(mutation{kind: mutationRemove, parent: parent, node: target, apply: func() {
+		parent.Call("removeChild", target)
+	}})
+}
+
+// SetAttribute sets name to value on el, deferring the write if a BatchDOM is open
+func (el *WrappedElement) SetAttribute(name, value string) {
+	parent := el.Underlying()
+	recordOrApply(mutation{kind: mutationSetAttribute, parent: parent, apply: func() {
+		el.UnwrappedElement.SetAttribute(name, value)
+	}})
+}
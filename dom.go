@@ -70,9 +70,14 @@ func typeDomElement(el dom.Element) Element {
 	switch wrapped.TagName() {
 	// We put the template case last so we can continue into the default case if we don't hit a template of a type we care about
 	case "TEMPLATE":
-		switch wrapped.GetAttribute("is") {
-		case "dom-bind-go":
-			return &AutoBindGoTemplate{wrapped}
+		is := wrapped.GetAttribute("is")
+		if is == "dom-bind-go" {
+			return &AutoBindGoTemplate{WrappedElement: wrapped}
+		}
+		// Templates registered through RegisterTemplateExtension (e.g. "dom-repeat-go", "dom-if-go")
+		// also get the Stamp/Bind/Unbind binding bridge; the extension itself is installed in Stamp
+		if _, ok := templateExtensions[is]; ok {
+			return &AutoBindGoTemplate{WrappedElement: wrapped}
 		}
 		fallthrough
 	default:
@@ -272,6 +277,43 @@ type Element interface {
 
 	// UnsubscribeEvent unsubscribes from the event with the passed handle
 	UnsubscribeEvent(sub *EventSubscription)
+
+	// SubscribeEventOpts subscribes to an event like SubscribeEvent, but accepts EventOptions to
+	// control capture/once/passive semantics and to delegate the listener to descendants matching
+	// a CSS selector. callback is invoked with an Event wrapper whose Target/CurrentTarget/Path
+	// resolve to Go Element/Node values rather than raw *js.Object.
+	SubscribeEventOpts(event string, callback interface{}, opts EventOptions) *EventSubscription
+
+	// DispatchCustomEvent constructs a CustomEvent named name carrying detail, dispatches it per
+	// opts, and returns false if a listener called preventDefault on a cancelable event
+	DispatchCustomEvent(name string, detail interface{}, opts DispatchOptions) bool
+
+	// ObservePath compiles path (e.g. "user.address.city" or "items.3.name") into its segments and
+	// calls cb with the reified new/old leaf values whenever the leaf value changes. On a node with
+	// Polymer's notifyPath/set bridge only the full path's "-changed" event is observed, not one per
+	// segment. path may end in the wildcard suffixes "*" or "splices" to report array
+	// mutations as []IndexSplice instead of a plain new/old pair.
+	ObservePath(path string, cb func(newVal, oldVal interface{})) *PathObserver
+	// UnobservePath stops an observer started with ObservePath
+	UnobservePath(obs *PathObserver)
+
+	// CreateShadowRoot creates a v0 shadow root on the element and returns it wrapped as a ShadowRoot
+	CreateShadowRoot(mode string) ShadowRoot
+	// AttachShadow attaches a v1 shadow root to the element per opts and returns it wrapped as a
+	// ShadowRoot
+	AttachShadow(opts ShadowRootInit) ShadowRoot
+	// ShadowRoot returns the ShadowRoot already attached to this element, or nil if none has been
+	// created yet
+	ShadowRoot() ShadowRoot
+	// Host returns the shadow host of the ShadowRoot this element is rendered in, or nil if the
+	// element isn't distributed into a shadow tree
+	Host() Element
+	// Slotted returns the nodes currently assigned to the <slot> with the given name, bridging both
+	// the v0 <content select> and v1 <slot name> distribution models
+	Slotted(name string) []dom.Node
+	// AssignedSlot returns the <slot> element this element is currently assigned to, or nil if it
+	// isn't assigned to one
+	AssignedSlot() Element
 }
 
 func (el *WrappedElement) TagName() string {
@@ -289,10 +331,6 @@ func (el *WrappedElement) ParentElement() dom.Element {
 	return WrapDOMElement(el.UnwrappedElement.ParentElement())
 }
 
-func (el *WrappedElement) InsertBefore(which dom.Node, before dom.Node) {
-	el.Underlying().Call("insertBefore", unwrap(which.Underlying()), unwrap(before.Underlying()))
-}
-
 func (el *WrappedElement) GetElementsByClassName(name string) []dom.Element {
 	return el.QuerySelectorAll("." + name)
 }
@@ -305,10 +343,6 @@ func (el *WrappedElement) GetElementsByTagNameNS(ns, name string) []dom.Element
 	panic("Operation not supported")
 }
 
-func (el *WrappedElement) AppendChild(node dom.Node) {
-	el.Underlying().Call("appendChild", unwrap(node.Underlying()))
-}
-
 func (el *WrappedElement) QuerySelector(sel string) dom.Element {
 	return WrapDOMElement(el.Element.QuerySelector(sel))
 }
@@ -333,6 +367,7 @@ type EventSubscription struct {
 	event      string
 	funcObj    *js.Object
 	chanRefVal reflect.Value
+	capture    bool
 }
 
 func (el *WrappedElement) SubscribeEvent(event string, callback interface{}) *EventSubscription {
@@ -353,7 +388,7 @@ func (el *WrappedElement) SubscribeEvent(event string, callback interface{}) *Ev
 }
 
 func (el *WrappedElement) UnsubscribeEvent(sub *EventSubscription) {
-	el.Underlying().Call("removeEventListener", sub.event, sub.funcObj)
+	el.Underlying().Call("removeEventListener", sub.event, sub.funcObj, sub.capture)
 	if sub.chanRefVal.IsValid() {
 		sub.chanRefVal.Close()
 	}
@@ -0,0 +1,137 @@
+/*
+Copyright 2015 Palm Stone Games, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymer
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+	"reflect"
+)
+
+// EventOptions configures a SubscribeEventOpts subscription
+// Capture, Once and Passive mirror the native addEventListener options object. Selector, when set,
+// turns the subscription into a delegated listener: the callback only fires when the retargeted
+// event target matches the selector, and the listener is attached once on the element itself.
+type EventOptions struct {
+	Capture  bool
+	Once     bool
+	Passive  bool
+	Selector string
+}
+
+// DispatchOptions configures the CustomEvent constructed by DispatchCustomEvent
+type DispatchOptions struct {
+	Bubbles    bool
+	Composed   bool
+	Cancelable bool
+}
+
+// Event wraps the *js.Object handed to an event listener, resolving ShadowDOM-retargeted
+// target/currentTarget and path information back to Go Element/Node values instead of leaving
+// callers to walk js.Object themselves
+type Event struct {
+	*js.Object
+}
+
+// Target returns the retargeted event target, resolved through Polymer.dom(event) the same way the
+// polyfill wrappers retarget it for listeners attached inside the local DOM
+func (e Event) Target() Element {
+	return WrapJSElement(polymerDOM(e.Object).Get("target"))
+}
+
+// CurrentTarget returns the element the listener was attached to, resolved the same way as Target
+func (e Event) CurrentTarget() Element {
+	return WrapJSElement(polymerDOM(e.Object).Get("currentTarget"))
+}
+
+// Path returns the composed path the event traveled, from target to window, as Polymer.dom(event)
+// reports it. This is equivalent to the native composedPath() but remains scope-aware for browsers
+// relying on the ShadowDOM polyfill.
+func (e Event) Path() []dom.Node {
+	return objToNodeSlice(polymerDOM(e.Object).Get("path"))
+}
+
+// ComposedPath is an alias for Path, matching the name of the native Event.composedPath API
+func (e Event) ComposedPath() []dom.Node {
+	return e.Path()
+}
+
+// SubscribeEventOpts subscribes to an event like SubscribeEvent, but accepts EventOptions to
+// control capture/once/passive semantics and, via Selector, delegate the listener to descendants
+// matching a CSS selector rather than firing for every bubbled event.
+// callback must be a func taking a single Event: it is invoked with the retargeted Event wrapper so
+// Target()/CurrentTarget()/Path() are available. The typed callbacks and channels accepted by
+// SubscribeEvent are not supported here.
+func (el *WrappedElement) SubscribeEventOpts(event string, callback interface{}, opts EventOptions) *EventSubscription {
+	refVal := reflect.ValueOf(callback)
+	if refVal.Kind() != reflect.Func {
+		panic("SubscribeEventOpts requires a func callback")
+	}
+
+	sub := &EventSubscription{event: event, capture: opts.Capture}
+	sub.funcObj = js.MakeFunc(func(this *js.Object, arguments []*js.Object) interface{} {
+		jsEvent := arguments[0]
+		evt := Event{jsEvent}
+
+		if opts.Selector != "" {
+			target := evt.Target()
+			if target == nil || !elementMatches(target, opts.Selector) {
+				return nil
+			}
+		}
+
+		if opts.Once {
+			el.UnsubscribeEvent(sub)
+		}
+
+		refVal.Call([]reflect.Value{reflect.ValueOf(evt)})
+		return nil
+	})
+
+	listenerOpts := js.Global.Get("Object").New()
+	listenerOpts.Set("capture", opts.Capture)
+	// When delegating via Selector, "once" can't be handed to the native listener: the browser
+	// would auto-detach after the first event reaches el even if it doesn't match the selector.
+	// UnsubscribeEvent is called explicitly above, only once a match has actually fired.
+	listenerOpts.Set("once", opts.Once && opts.Selector == "")
+	listenerOpts.Set("passive", opts.Passive)
+
+	el.Underlying().Get("node").Call("addEventListener", event, sub.funcObj, listenerOpts)
+	return sub
+}
+
+// elementMatches reports whether el matches selector, used to filter delegated listeners
+// registered through SubscribeEventOpts' Selector option
+func elementMatches(el Element, selector string) bool {
+	return el.Underlying().Call("matches", selector).Bool()
+}
+
+// DispatchCustomEvent constructs and dispatches a CustomEvent named name on el, carrying detail as
+// its detail payload. detail is attached as js.InternalObject(detail), the Go value's internal
+// gopherjs representation; it is not marshalled through reflect into plain JS values. It returns false if the event was cancelable and a listener called preventDefault on
+// it. Named DispatchCustomEvent, rather than DispatchEvent, to avoid colliding with the
+// DispatchEvent(dom.Event) bool method WrappedElement already inherits from dom.Element.
+func (el *WrappedElement) DispatchCustomEvent(name string, detail interface{}, opts DispatchOptions) bool {
+	init := js.Global.Get("Object").New()
+	init.Set("bubbles", opts.Bubbles)
+	init.Set("composed", opts.Composed)
+	init.Set("cancelable", opts.Cancelable)
+	init.Set("detail", js.InternalObject(detail))
+
+	event := js.Global.Get("CustomEvent").New(name, init)
+	return el.Underlying().Get("node").Call("dispatchEvent", event).Bool()
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2015 Palm Stone Games, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymer
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathObserverPollInterval is how often the polling fallback re-reads the path
+// when the underlying node doesn't expose Polymer's notifyPath/set bridge.
+const pathObserverPollInterval = 125
+
+// IndexSplice describes a single array mutation reported by a "path.*" or "path.splices" observation
+// Its fields mirror the splice records produced by observe-js/Array.observe
+type IndexSplice struct {
+	Index      int
+	Removed    []interface{}
+	AddedCount int
+}
+
+// PathObserver tracks a single ObservePath subscription
+// It is returned from ObservePath and should be passed to UnobservePath to tear the subscription down
+type PathObserver struct {
+	element  *WrappedElement
+	path     string
+	segments []string
+	wildcard bool
+	cb       func(newVal, oldVal interface{})
+
+	last       interface{}
+	listeners  []pathListener
+	pollHandle *js.Object
+}
+
+// pathListener is a single addEventListener registration backing a PathObserver
+// bindNotifyPath registers one listener for the whole path's "<path>-changed" event; no per-segment
+// listeners are registered
+type pathListener struct {
+	node  *js.Object
+	event string
+	fn    func(*js.Object)
+}
+
+// compilePath splits a dotted/bracketed path such as "user.address.city" or "items.3.name" into segments
+// Bracketed indices (items[3].name) are normalized to dotted form before splitting
+func compilePath(path string) []string {
+	normalized := strings.Replace(strings.Replace(path, "[", ".", -1), "]", "", -1)
+	segments := strings.Split(normalized, ".")
+	out := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ObservePath compiles path into its segments and invokes cb whenever the reified leaf value
+// changes. path supports wildcard suffixes, "items.*" and "items.splices", which report array
+// mutations via IndexSplice rather than a plain new/old pair.
+// Where the underlying node exposes Polymer's notifyPath/set bridge, ObservePath registers a single
+// listener for the full path's "<path>-changed" event; changes announced for a shorter prefix of
+// path are not observed. Otherwise it falls back to polling the whole path and diffing the decoded
+// value, mirroring the Object.observe shim in the ShadowDOM polyfill sources.
+func (el *WrappedElement) ObservePath(path string, cb func(newVal, oldVal interface{})) *PathObserver {
+	segments := compilePath(path)
+	last := len(segments) - 1
+
+	obs := &PathObserver{
+		element:  el,
+		path:     path,
+		segments: segments,
+		cb:       cb,
+	}
+
+	if last >= 0 && (segments[last] == "*" || segments[last] == "splices") {
+		// Both the "*" and "splices" wildcard suffixes observe array mutations and report them as
+		// []IndexSplice rather than a plain new/old pair
+		obs.wildcard = true
+		segments = segments[:last]
+	}
+	obs.segments = segments
+
+	node := el.Underlying().Get("node")
+	if node != nil && node != js.Undefined && isPolymerBridgedNode(node) {
+		obs.bindNotifyPath(node)
+	} else {
+		obs.bindPolling()
+	}
+
+	return obs
+}
+
+// isPolymerBridgedNode reports whether node exposes Polymer's notifyPath/set data binding bridge
+func isPolymerBridgedNode(node *js.Object) bool {
+	return node.Get("notifyPath") != js.Undefined && node.Get("set") != js.Undefined
+}
+
+// bindNotifyPath wires up a single "<path>-changed" listener, which is how Polymer elements
+// announce deep property changes once notifyPath/set has been called on them
+func (obs *PathObserver) bindNotifyPath(node *js.Object) {
+	event := strings.Join(obs.segments, ".") + "-changed"
+	if obs.wildcard {
+		event = strings.Join(obs.segments, ".") + ".splices-changed"
+	}
+
+	fn := func(jsEvent *js.Object) {
+		detail := jsEvent.Get("detail")
+
+		if obs.wildcard {
+			obs.cb(decodeSpliceRecords(detail.Get("value")), nil)
+			return
+		}
+
+		newVal := decodePathValue(detail.Get("value"))
+		oldVal := obs.last
+		obs.last = newVal
+		obs.cb(newVal, oldVal)
+	}
+
+	node.Call("addEventListener", event, fn)
+	obs.listeners = append(obs.listeners, pathListener{node: node, event: event, fn: fn})
+}
+
+// bindPolling reads the path off the underlying node on an interval and fires cb when the decoded
+// value has changed since the last read, for nodes that don't implement notifyPath/set
+func (obs *PathObserver) bindPolling() {
+	node := obs.element.Underlying().Get("node")
+	obs.last = obs.readPath(node)
+
+	obs.pollHandle = js.Global.Call("setInterval", func() {
+		current := obs.readPath(node)
+
+		if obs.wildcard {
+			if splices := diffToSplices(obs.last, current); len(splices) > 0 {
+				obs.last = current
+				obs.cb(splices, nil)
+			}
+			return
+		}
+
+		if !pathValuesEqual(current, obs.last) {
+			old := obs.last
+			obs.last = current
+			obs.cb(current, old)
+		}
+	}, pathObserverPollInterval)
+}
+
+// diffToSplices compares oldVal and newVal, both expected to be the []interface{} readPath decodes
+// an array path into, and reports the difference as a single []IndexSplice the same way
+// decodeSpliceRecords does for the notifyPath path, so bindPolling's wildcard observers behave like
+// bindNotifyPath's. It trims the common prefix/suffix and reports everything in between as one
+// splice, which isn't a minimal diff but matches the shape callers are contractually given.
+func diffToSplices(oldVal, newVal interface{}) []IndexSplice {
+	oldArr, _ := oldVal.([]interface{})
+	newArr, _ := newVal.([]interface{})
+
+	prefix := 0
+	for prefix < len(oldArr) && prefix < len(newArr) && reflect.DeepEqual(oldArr[prefix], newArr[prefix]) {
+		prefix++
+	}
+
+	oldEnd, newEnd := len(oldArr), len(newArr)
+	for oldEnd > prefix && newEnd > prefix && reflect.DeepEqual(oldArr[oldEnd-1], newArr[newEnd-1]) {
+		oldEnd--
+		newEnd--
+	}
+
+	if oldEnd == prefix && newEnd == prefix {
+		return nil
+	}
+
+	removed := make([]interface{}, oldEnd-prefix)
+	copy(removed, oldArr[prefix:oldEnd])
+
+	return []IndexSplice{{
+		Index:      prefix,
+		Removed:    removed,
+		AddedCount: newEnd - prefix,
+	}}
+}
+
+// readPath walks obs.segments off node, returning nil if any intermediate segment is missing
+func (obs *PathObserver) readPath(node *js.Object) interface{} {
+	current := node
+	for _, segment := range obs.segments {
+		if current == nil || current == js.Undefined {
+			return nil
+		}
+		if index, err := strconv.Atoi(segment); err == nil {
+			current = current.Index(index)
+		} else {
+			current = current.Get(segment)
+		}
+	}
+	return decodePathValue(current)
+}
+
+// decodePathValue reifies a leaf *js.Object into a plain Go value understood by callers
+func decodePathValue(obj *js.Object) interface{} {
+	if obj == nil || obj == js.Undefined {
+		return nil
+	}
+	return obj.Interface()
+}
+
+// decodeSpliceRecords decodes the array of splice records Polymer reports on an "items.splices" path
+func decodeSpliceRecords(obj *js.Object) []IndexSplice {
+	if obj == nil || obj == js.Undefined {
+		return nil
+	}
+
+	indexSplices := obj.Get("indexSplices")
+	splices := make([]IndexSplice, indexSplices.Length())
+	for i := range splices {
+		s := indexSplices.Index(i)
+		removed := s.Get("removed")
+		splices[i] = IndexSplice{
+			Index:      s.Get("index").Int(),
+			AddedCount: s.Get("addedCount").Int(),
+			Removed:    make([]interface{}, removed.Length()),
+		}
+		for j := range splices[i].Removed {
+			splices[i].Removed[j] = decodePathValue(removed.Index(j))
+		}
+	}
+	return splices
+}
+
+// pathValuesEqual is the equality check used by the polling fallback to detect changes
+// reflect.DeepEqual is used rather than == because decoded JS objects/arrays come back as
+// map[string]interface{}/[]interface{}, which aren't comparable with ==
+func pathValuesEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// UnobservePath tears down a PathObserver started with ObservePath, removing any listeners it
+// registered or stopping its polling timer
+func (el *WrappedElement) UnobservePath(obs *PathObserver) {
+	for _, l := range obs.listeners {
+		l.node.Call("removeEventListener", l.event, l.fn)
+	}
+	if obs.pollHandle != nil {
+		js.Global.Call("clearInterval", obs.pollHandle)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2015 Palm Stone Games, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymer
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// ShadowRootInit mirrors the options bag accepted by the native Element.attachShadow
+type ShadowRootInit struct {
+	Mode string
+}
+
+// ShadowRoot is a shadow-scoped view onto an Element's local DOM
+// Unlike the light-DOM proxy returned by Root(), queries made through a ShadowRoot go through
+// Polymer.dom(root) and are retargeted/scoped the way the ShadowDOM polyfill describes
+type ShadowRoot interface {
+	// Host returns the Element this ShadowRoot is attached to
+	Host() Element
+
+	// QuerySelector returns the first shadow-scoped descendant that matches selector
+	QuerySelector(selector string) Element
+	// QuerySelectorAll returns all shadow-scoped descendants that match selector
+	QuerySelectorAll(selector string) []Element
+
+	// TreeScope returns the scope enclosing this ShadowRoot
+	TreeScope() TreeScope
+
+	// Underlying returns the wrapped *js.Object for the shadow root, for callers that need to drop
+	// down to the raw platform API
+	Underlying() *js.Object
+}
+
+// TreeScope represents the scope a node is rendered in, letting callers implement retargeted event
+// handling and scoped id lookup without reaching into js.Object directly
+type TreeScope interface {
+	// Root returns the root node of this scope: a ShadowRoot, or the owner document for the
+	// top-level document scope
+	Root() *js.Object
+	// GetElementByID looks up an element by id, scoped to this TreeScope
+	GetElementByID(id string) Element
+}
+
+type shadowRoot struct {
+	object *js.Object
+	host   Element
+}
+
+func (s *shadowRoot) Host() Element {
+	return s.host
+}
+
+func (s *shadowRoot) QuerySelector(selector string) Element {
+	return WrapJSElement(polymerDOM(s.object).Call("querySelector", selector))
+}
+
+func (s *shadowRoot) QuerySelectorAll(selector string) []Element {
+	return objToElementSlice(polymerDOM(s.object).Call("querySelectorAll", selector))
+}
+
+func (s *shadowRoot) TreeScope() TreeScope {
+	return &treeScope{root: s.object}
+}
+
+func (s *shadowRoot) Underlying() *js.Object {
+	return s.object
+}
+
+type treeScope struct {
+	root *js.Object
+}
+
+func (t *treeScope) Root() *js.Object {
+	return t.root
+}
+
+func (t *treeScope) GetElementByID(id string) Element {
+	return WrapJSElement(polymerDOM(t.root).Call("getElementById", id))
+}
+
+// CreateShadowRoot creates a v0 shadow root on the element in the given mode ("open" or "closed")
+// and returns it wrapped as a ShadowRoot
+func (el *WrappedElement) CreateShadowRoot(mode string) ShadowRoot {
+	object := el.Underlying().Get("node").Call("createShadowRoot")
+	object.Set("mode", mode)
+	return &shadowRoot{object: object, host: el}
+}
+
+// AttachShadow attaches a v1 shadow root to the element per opts and returns it wrapped as a
+// ShadowRoot
+func (el *WrappedElement) AttachShadow(opts ShadowRootInit) ShadowRoot {
+	init := js.Global.Get("Object").New()
+	init.Set("mode", opts.Mode)
+	object := el.Underlying().Get("node").Call("attachShadow", init)
+	return &shadowRoot{object: object, host: el}
+}
+
+// ShadowRoot returns the ShadowRoot already attached to this element, or nil if none has been
+// created yet
+func (el *WrappedElement) ShadowRoot() ShadowRoot {
+	object := el.Underlying().Get("node").Get("shadowRoot")
+	if object == nil || object == js.Undefined {
+		return nil
+	}
+	return &shadowRoot{object: object, host: el}
+}
+
+// Host returns the shadow host of the ShadowRoot this element is rendered in, or nil if the
+// element isn't distributed into a shadow tree
+func (el *WrappedElement) Host() Element {
+	root := el.Underlying().Call("getOwnerRoot")
+	if root == nil || root == js.Undefined {
+		return nil
+	}
+	host := root.Get("host")
+	if host == nil || host == js.Undefined {
+		return nil
+	}
+	return WrapJSElement(host)
+}
+
+// Slotted returns the nodes currently assigned to the <slot> with the given name, bridging both
+// the v0 <content select> and v1 <slot name> distribution models
+func (el *WrappedElement) Slotted(name string) []dom.Node {
+	node := el.Underlying().Get("node")
+
+	slotSelector := "slot"
+	if name != "" {
+		slotSelector = "slot[name=\"" + name + "\"]"
+	}
+	if slot := polymerDOM(node).Call("querySelector", slotSelector); slot != nil && slot != js.Undefined && slot.Get("assignedNodes") != js.Undefined {
+		return objToNodeSlice(slot.Call("assignedNodes"))
+	}
+
+	// Fall back to v0 <content> distribution, as already exposed via GetDistributedNodes
+	contentSelector := "content"
+	if name != "" {
+		contentSelector = "content[select=\"" + name + "\"]"
+	}
+	content := polymerDOM(node).Call("querySelector", contentSelector)
+	if content == nil || content == js.Undefined {
+		return nil
+	}
+	return objToNodeSlice(content.Call("getDistributedNodes"))
+}
+
+// AssignedSlot returns the <slot> element this element is currently assigned to, or nil if it
+// isn't assigned to one
+func (el *WrappedElement) AssignedSlot() Element {
+	slot := el.Underlying().Get("node").Get("assignedSlot")
+	if slot == nil || slot == js.Undefined {
+		return nil
+	}
+	return WrapJSElement(slot)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2015 Palm Stone Games, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package polymer
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+	"reflect"
+)
+
+// AutoBindGoTemplate wraps a <template is="dom-bind-go"> element
+// It is the Go-side entry point for the TemplateBinding subsystem: stamping instances from a Go
+// model and binding individual model properties to PathObservers for two-way data binding.
+type AutoBindGoTemplate struct {
+	*WrappedElement
+
+	instance *TemplateInstance
+}
+
+// TemplateInstance is a single stamped instance of an AutoBindGoTemplate's content
+// It keeps its root nodes, and the PathObservers backing its property bindings so Unbind can tear
+// those bindings down deterministically.
+type TemplateInstance struct {
+	template *AutoBindGoTemplate
+	model    interface{}
+
+	roots     []dom.Node
+	bindings  map[string]*Binding
+	extension TemplateExtension
+}
+
+// Binding is a single two-way property binding created by AutoBindGoTemplate.Bind
+// property-changed notifications raised on the template node are written onto the template's
+// model; path is held so that Unbind can stop its PathObserver.
+type Binding struct {
+	property string
+	observer *PathObserver
+	node     *js.Object
+	listener func(*js.Object)
+}
+
+// TemplateExtension is implemented by custom template controllers registered through
+// RegisterTemplateExtension, mirroring the role dom-repeat/dom-if play for the built-in templates
+type TemplateExtension interface {
+	// Attach is called once, when the extension is installed onto a stamped template element
+	Attach(el dom.Element)
+	// Detach is called when the template is stamped again, before the new instance's extension is
+	// attached
+	Detach()
+}
+
+// templateExtensions holds the factories registered via RegisterTemplateExtension, keyed by the
+// "is" attribute value they handle (e.g. "dom-repeat-go")
+var templateExtensions = map[string]func(dom.Element) TemplateExtension{}
+
+// RegisterTemplateExtension installs factory as the handler for <template is="name">, letting
+// callers implement custom controllers such as "dom-repeat-go" or "dom-if-go" on top of the same
+// stamping/binding machinery AutoBindGoTemplate uses.
+func RegisterTemplateExtension(name string, factory func(el dom.Element) TemplateExtension) {
+	templateExtensions[name] = factory
+}
+
+// Stamp clones the template's content, binds it against model, and returns the resulting
+// TemplateInstance. The instance becomes the target of any Bind/Unbind calls made on the template
+// afterwards, matching how Polymer's own Node.bind/TemplateBinding layer scopes bindings to the
+// most recently stamped instance.
+func (t *AutoBindGoTemplate) Stamp(model interface{}) TemplateInstance {
+	if t.instance != nil && t.instance.extension != nil {
+		t.instance.extension.Detach()
+	}
+
+	node := t.Underlying().Get("node")
+	node.Set("model", js.InternalObject(model))
+
+	fragment := node.Call("stamp", js.InternalObject(model))
+	instance := &TemplateInstance{
+		template: t,
+		model:    model,
+		roots:    objToNodeSlice(fragment.Get("childNodes")),
+		bindings: map[string]*Binding{},
+	}
+	t.instance = instance
+
+	if is := t.GetAttribute("is"); is != "" {
+		if factory, ok := templateExtensions[is]; ok {
+			instance.extension = factory(t)
+			instance.extension.Attach(t)
+		}
+	}
+
+	return *instance
+}
+
+// Bind binds property on the most recently stamped model: "<property>-changed" notifications raised
+// on the template node are decoded and written onto the model's field named property via
+// reflection. path is kept with the binding so Unbind can stop it; Bind does not write values
+// observed on path onto the model, and nothing is written back into path's underlying value.
+func (t *AutoBindGoTemplate) Bind(property string, path *PathObserver) *Binding {
+	if t.instance == nil {
+		panic("Bind called before Stamp: a template must be stamped before its properties can be bound")
+	}
+
+	// Re-binding property without an intervening Unbind would otherwise leak the previous
+	// listener and PathObserver (including its polling setInterval, which never stops on its own)
+	t.Unbind(property)
+
+	node := t.Underlying().Get("node")
+	listener := func(jsEvent *js.Object) {
+		writeModelProperty(t.instance.model, property, decodePathValue(jsEvent.Get("detail").Get("value")))
+	}
+	node.Call("addEventListener", property+"-changed", listener)
+
+	binding := &Binding{property: property, observer: path, node: node, listener: listener}
+	t.instance.bindings[property] = binding
+
+	return binding
+}
+
+// Unbind tears down the binding previously created for property with Bind: it removes the
+// property-changed listener Bind registered and stops path's PathObserver from delivering further
+// notifications
+func (t *AutoBindGoTemplate) Unbind(property string) {
+	if t.instance == nil {
+		return
+	}
+
+	if binding, ok := t.instance.bindings[property]; ok {
+		binding.node.Call("removeEventListener", property+"-changed", binding.listener)
+		t.UnobservePath(binding.observer)
+		delete(t.instance.bindings, property)
+	}
+}
+
+// writeModelProperty reflects value onto the field named property of model, so two-way bindings
+// created with AutoBindGoTemplate.Bind can push child element changes back into Go state.
+// Values decoded from JS numbers (float64) are converted to the field's own numeric type rather
+// than assigned directly, since gopherjs decodes every JS number the same way regardless of the
+// Go field's width. A nil value, which decodePathValue returns for an undefined JS value, sets the
+// field back to its zero value rather than reaching into reflect.ValueOf(nil), which has no Type().
+func writeModelProperty(model interface{}, property string, value interface{}) {
+	modelVal := reflect.ValueOf(model)
+	if modelVal.Kind() == reflect.Ptr {
+		modelVal = modelVal.Elem()
+	}
+
+	field := modelVal.FieldByName(property)
+	if !field.IsValid() || !field.CanSet() {
+		return
+	}
+
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+
+	valueVal := reflect.ValueOf(value)
+	if !valueVal.Type().AssignableTo(field.Type()) {
+		if !valueVal.Type().ConvertibleTo(field.Type()) {
+			return
+		}
+		valueVal = valueVal.Convert(field.Type())
+	}
+	field.Set(valueVal)
+}